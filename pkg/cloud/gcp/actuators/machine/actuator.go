@@ -6,15 +6,22 @@ package machine
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	clusterv1 "github.com/openshift/cluster-api/pkg/apis/cluster/v1alpha1"
 	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	mapiclient "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset/typed/machine/v1beta1"
 	apierrors "github.com/openshift/cluster-api/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	controllerclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openshift/cluster-api-provider-gcp/pkg/cloud/gcp/actuators/machine/driver"
+	"github.com/openshift/cluster-api-provider-gcp/pkg/util/ssa"
 )
 
 const (
@@ -30,6 +37,24 @@ type Actuator struct {
 	machineClient mapiclient.MachineV1beta1Interface
 	coreClient    controllerclient.Client
 	eventRecorder record.EventRecorder
+
+	// controller and deletionEvents back the dynamic watches registered by
+	// ensureDeletionWatch: Watch is set up once against controller, and
+	// watches enqueue reconciles by sending generic events on the channel.
+	controller        controller.Controller
+	deletionEvents    chan event.GenericEvent
+	deletionWatches   map[types.UID]*deletionWatch
+	deletionWatchesMu sync.Mutex
+
+	// driverClient, when non-nil, dispatches Create/Update/Delete/Exists to
+	// an out-of-tree machine driver plugin instead of reconciling the
+	// instance in-tree. It is set up from ActuatorParams.DriverEndpoint.
+	driverClient driver.MachineDriverClient
+
+	// ssaCache short-circuits persistMachine's Server-Side Apply patch when
+	// an identical apply configuration was already sent for the machine's
+	// current resourceVersion.
+	ssaCache *ssa.Cache
 }
 
 // ActuatorParams holds parameter information for Actuator.
@@ -37,15 +62,43 @@ type ActuatorParams struct {
 	MachineClient mapiclient.MachineV1beta1Interface
 	CoreClient    controllerclient.Client
 	EventRecorder record.EventRecorder
+
+	// DriverEndpoint, when set, is the address of an external gRPC machine
+	// driver plugin (modeled on Gardener MCM's driver interface) that the
+	// Actuator dispatches Create/Update/Delete/Exists to instead of
+	// reconciling the instance in-tree. Leave empty to use the in-tree
+	// reconciler.
+	DriverEndpoint string
+
+	// Controller, when set, is the controller this Actuator's machine
+	// reconcile loop runs under. It is used to register the dynamic
+	// deletion watch (see SetupDeletionWatch) so Delete can enqueue a
+	// reconcile as soon as an in-progress instance deletion completes,
+	// instead of waiting out the resync period.
+	Controller controller.Controller
 }
 
 // NewActuator returns an actuator.
-func NewActuator(params ActuatorParams) *Actuator {
-	return &Actuator{
+func NewActuator(params ActuatorParams) (*Actuator, error) {
+	a := &Actuator{
 		machineClient: params.MachineClient,
 		coreClient:    params.CoreClient,
 		eventRecorder: params.EventRecorder,
+		ssaCache:      ssa.NewCache(0),
+	}
+	if params.DriverEndpoint != "" {
+		driverClient, err := dialDriver(params.DriverEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		a.driverClient = driverClient
+	}
+	if params.Controller != nil {
+		if err := a.SetupDeletionWatch(params.Controller); err != nil {
+			return nil, err
+		}
 	}
+	return a, nil
 }
 
 // Set corresponding event based on error. It also returns the original error
@@ -62,6 +115,13 @@ func (a *Actuator) handleMachineError(machine *machinev1.Machine, err *apierrors
 // Create creates a machine and is invoked by the machine controller.
 func (a *Actuator) Create(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) error {
 	klog.Infof("%s: Creating machine", machine.Name)
+	if a.driverClient != nil {
+		if err := a.driverCreate(ctx, machine); err != nil {
+			return a.handleMachineError(machine, apierrors.CreateMachine(err.Error()), createEventAction)
+		}
+		a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, createEventAction, "Created Machine %v", machine.Name)
+		return nil
+	}
 	scope, err := newMachineScope(machineScopeParams{
 		machineClient: a.machineClient,
 		coreClient:    a.coreClient,
@@ -75,11 +135,18 @@ func (a *Actuator) Create(ctx context.Context, cluster *clusterv1.Cluster, machi
 		return a.handleMachineError(machine, apierrors.CreateMachine(err.Error()), createEventAction)
 	}
 	a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, createEventAction, "Created Machine %v", machine.Name)
-	return scope.Close()
+	// scope.machine is the same pointer passed into machineScopeParams above,
+	// so create()'s providerID/status writes are visible on it here too; we
+	// persist that pointer explicitly rather than relying on the call site
+	// always holding the scope's own copy.
+	return a.persistMachine(ctx, scope.machine)
 }
 
 func (a *Actuator) Exists(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) (bool, error) {
 	klog.Infof("%s: Checking if machine exists", machine.Name)
+	if a.driverClient != nil {
+		return a.driverExists(ctx, machine)
+	}
 	scope, err := newMachineScope(machineScopeParams{
 		machineClient: a.machineClient,
 		coreClient:    a.coreClient,
@@ -89,15 +156,21 @@ func (a *Actuator) Exists(ctx context.Context, cluster *clusterv1.Cluster, machi
 		return false, fmt.Errorf(scopeFailFmt, machine.Name, err)
 	}
 	// The core machine controller calls exists() + create()/update() in the same reconciling operation.
-	// If exists() would store machineSpec/status object then create()/update() would still receive the local version.
-	// When create()/update() try to store machineSpec/status this might result in
-	// "Operation cannot be fulfilled; the object has been modified; please apply your changes to the latest version and try again."
-	// Therefore we don't close the scope here and we only store spec/status atomically either in create()/update()"
+	// Persistence now goes through persistMachine's Server-Side Apply patch, which is commutative with
+	// whatever create()/update() applies afterwards, so unlike the old update-based path there's no
+	// "object has been modified" conflict to avoid by leaving this unpersisted.
 	return newReconciler(scope).exists()
 }
 
 func (a *Actuator) Update(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) error {
 	klog.Infof("%s: Updating machine", machine.Name)
+	if a.driverClient != nil {
+		if err := a.driverUpdate(ctx, machine); err != nil {
+			return a.handleMachineError(machine, apierrors.UpdateMachine(err.Error()), updateEventAction)
+		}
+		a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, updateEventAction, "Updated Machine %v", machine.Name)
+		return nil
+	}
 	scope, err := newMachineScope(machineScopeParams{
 		machineClient: a.machineClient,
 		coreClient:    a.coreClient,
@@ -111,11 +184,20 @@ func (a *Actuator) Update(ctx context.Context, cluster *clusterv1.Cluster, machi
 		return a.handleMachineError(machine, apierrors.UpdateMachine(err.Error()), updateEventAction)
 	}
 	a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, updateEventAction, "Updated Machine %v", machine.Name)
-	return scope.Close()
+	// See the comment in Create: persist scope's machine pointer, which is
+	// the one update() actually mutated.
+	return a.persistMachine(ctx, scope.machine)
 }
 
 func (a *Actuator) Delete(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) error {
 	klog.Infof("%s: Deleting machine", machine.Name)
+	if a.driverClient != nil {
+		if err := a.driverDelete(ctx, machine); err != nil {
+			return a.handleMachineError(machine, apierrors.DeleteMachine(err.Error()), deleteEventAction)
+		}
+		a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, deleteEventAction, "Deleted machine %v", machine.Name)
+		return nil
+	}
 	scope, err := newMachineScope(machineScopeParams{
 		machineClient: a.machineClient,
 		coreClient:    a.coreClient,
@@ -125,9 +207,26 @@ func (a *Actuator) Delete(ctx context.Context, cluster *clusterv1.Cluster, machi
 		fmtErr := fmt.Sprintf(scopeFailFmt, machine.Name, err)
 		return a.handleMachineError(machine, apierrors.DeleteMachine(fmtErr), deleteEventAction)
 	}
-	if err := newReconciler(scope).delete(); err != nil {
+	reconciler := newReconciler(scope)
+	if err := reconciler.delete(); err != nil {
 		return a.handleMachineError(machine, apierrors.DeleteMachine(err.Error()), deleteEventAction)
 	}
+
+	// delete() may return nil while the underlying GCE instance is still
+	// shutting down. Rather than wait out the full resync period for the
+	// next reconcile, register a watch that enqueues one as soon as the
+	// instance is actually gone, and keep the Machine (and its finalizer)
+	// around by returning an error here: reporting success while the
+	// instance still exists would let the machine controller delete the
+	// Machine out from under it, orphaning the instance.
+	if stillThere, err := reconciler.exists(); err != nil {
+		klog.Errorf("%s: failed to confirm instance deletion: %v", machine.Name, err)
+	} else if stillThere {
+		a.ensureDeletionWatch(machine)
+		return fmt.Errorf("%s: instance still present, waiting for deletion watch", machine.Name)
+	}
+
+	a.removeDeletionWatch(machine.UID)
 	a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, deleteEventAction, "Deleted machine %v", machine.Name)
 	return nil
 }
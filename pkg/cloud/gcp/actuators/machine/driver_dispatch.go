@@ -0,0 +1,181 @@
+package machine
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/cluster-api-provider-gcp/pkg/cloud/gcp/actuators/machine/driver"
+)
+
+// unixSocketPrefix marks a DriverEndpoint as a local unix domain socket
+// rather than a network address. Traffic over a unix socket never leaves the
+// host, so it is the one case plaintext dial is allowed: the service account
+// credentials resolveProviderSecret hands the driver never cross the wire.
+const unixSocketPrefix = "unix://"
+
+// credentialsSecretProviderSpec is the subset of GCPMachineProviderSpec
+// resolveProviderSecret needs to find the credentials secret CAPG already
+// resolves for the in-tree reconciler.
+type credentialsSecretProviderSpec struct {
+	CredentialsSecret *corev1.LocalObjectReference `json:"credentialsSecret"`
+}
+
+// resolveProviderSecret fetches the raw credentials secret for machine so it
+// can be handed to an external driver, which has no access to the
+// management cluster's apiserver. The secret name comes from
+// providerSpec.credentialsSecret.name, the same field the in-tree reconciler
+// uses, not a name derived from the machine.
+func (a *Actuator) resolveProviderSecret(ctx context.Context, machine *machinev1.Machine) ([]byte, error) {
+	if machine.Spec.ProviderSpec.Value == nil {
+		return nil, fmt.Errorf("machine %s has no providerSpec to resolve a credentials secret from", machine.Name)
+	}
+	spec := &credentialsSecretProviderSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to decode providerSpec for machine %s: %v", machine.Name, err)
+	}
+	if spec.CredentialsSecret == nil {
+		return nil, fmt.Errorf("machine %s: providerSpec.credentialsSecret is not set", machine.Name)
+	}
+
+	secret := &corev1.Secret{}
+	key := apitypes.NamespacedName{Namespace: machine.Namespace, Name: spec.CredentialsSecret.Name}
+	if err := a.coreClient.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials secret %s for machine %s: %v", spec.CredentialsSecret.Name, machine.Name, err)
+	}
+	data, ok := secret.Data["service_account.json"]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("credentials secret %s for machine %s has no service_account.json key", spec.CredentialsSecret.Name, machine.Name)
+	}
+	return data, nil
+}
+
+// dialDriver opens a connection to the external machine driver listening at
+// endpoint. It is a var so tests can substitute a fake dialer.
+//
+// The connection requires TLS: the request newDriverRequest builds carries
+// the raw GCP service account JSON resolveProviderSecret fetches, so a plaintext dial would send
+// it over the wire in the clear. The one exception is a unix:// endpoint,
+// which never leaves the host the driver runs on.
+var dialDriver = func(endpoint string) (driver.MachineDriverClient, error) {
+	dialOpt := grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+	if strings.HasPrefix(endpoint, unixSocketPrefix) {
+		dialOpt = grpc.WithInsecure()
+	}
+	conn, err := grpc.Dial(endpoint, dialOpt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial machine driver at %s: %v", endpoint, err)
+	}
+	return driver.NewMachineDriverClient(conn), nil
+}
+
+// machineClassFor resolves the MachineClass an external driver should use
+// for machine. CAPG has no native concept of machine classes, so for now the
+// class name is just the machine's own name - good enough for a driver to
+// tell requests for different machines apart - and the providerSpec itself
+// is passed through verbatim.
+func machineClassFor(machine *machinev1.Machine) (*driver.MachineClass, error) {
+	if machine.Spec.ProviderSpec.Value == nil {
+		return &driver.MachineClass{}, nil
+	}
+	return &driver.MachineClass{
+		Name:         machine.Name,
+		ProviderSpec: machine.Spec.ProviderSpec.Value.Raw,
+	}, nil
+}
+
+// driverCreate dispatches Create to the external machine driver configured
+// on a instead of reconciling the instance in-tree.
+func (a *Actuator) driverCreate(ctx context.Context, machine *machinev1.Machine) error {
+	req, err := a.newDriverRequest(ctx, machine)
+	if err != nil {
+		return err
+	}
+	_, err = a.driverClient.CreateMachine(ctx, (*driver.CreateMachineRequest)(req))
+	return err
+}
+
+// driverUpdate dispatches Update to the external machine driver configured
+// on a. It calls UpdateMachine rather than reusing CreateMachine: the proto
+// contract requires a driver to implement UpdateMachine idempotently with
+// respect to no-op reconciles, so the provider no longer has to assume
+// CreateMachine itself is safe to call repeatedly with the current desired
+// state.
+func (a *Actuator) driverUpdate(ctx context.Context, machine *machinev1.Machine) error {
+	req, err := a.newDriverRequest(ctx, machine)
+	if err != nil {
+		return err
+	}
+	_, err = a.driverClient.UpdateMachine(ctx, (*driver.UpdateMachineRequest)(req))
+	return err
+}
+
+// driverDelete dispatches Delete to the external machine driver configured
+// on a instead of reconciling the instance in-tree.
+func (a *Actuator) driverDelete(ctx context.Context, machine *machinev1.Machine) error {
+	req, err := a.newDriverRequest(ctx, machine)
+	if err != nil {
+		return err
+	}
+	_, err = a.driverClient.DeleteMachine(ctx, &driver.DeleteMachineRequest{
+		MachineName:    req.MachineName,
+		MachineSpec:    req.MachineSpec,
+		ProviderSecret: req.ProviderSecret,
+		MachineClass:   req.MachineClass,
+	})
+	return err
+}
+
+// driverExists dispatches Exists to the external machine driver configured
+// on a instead of reconciling the instance in-tree.
+func (a *Actuator) driverExists(ctx context.Context, machine *machinev1.Machine) (bool, error) {
+	req, err := a.newDriverRequest(ctx, machine)
+	if err != nil {
+		return false, err
+	}
+	resp, err := a.driverClient.GetMachineStatus(ctx, &driver.GetMachineStatusRequest{
+		MachineName:    req.MachineName,
+		MachineSpec:    req.MachineSpec,
+		ProviderSecret: req.ProviderSecret,
+		MachineClass:   req.MachineClass,
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Exists, nil
+}
+
+// newDriverRequest assembles the fields common to every driver RPC: the
+// serialized machine spec, the resolved credentials secret and the machine
+// class. It is shaped as a CreateMachineRequest purely for field reuse:
+// driverCreate sends it as is, driverUpdate converts it to an
+// UpdateMachineRequest (the two have identical fields), and driverDelete and
+// driverExists copy its fields into their own request types.
+func (a *Actuator) newDriverRequest(ctx context.Context, machine *machinev1.Machine) (*driver.CreateMachineRequest, error) {
+	spec, err := json.Marshal(machine.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal machine spec: %v", err)
+	}
+	secret, err := a.resolveProviderSecret(ctx, machine)
+	if err != nil {
+		return nil, err
+	}
+	class, err := machineClassFor(machine)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.CreateMachineRequest{
+		MachineName:    machine.Name,
+		MachineSpec:    spec,
+		ProviderSecret: secret,
+		MachineClass:   class,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	clusterv1 "github.com/openshift/cluster-api/pkg/apis/cluster/v1alpha1"
+	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	apierrors "github.com/openshift/cluster-api/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	migrateEventAction = "Migrate"
+	restoreEventAction = "Restore"
+
+	// migrationStateAnnotation carries the JSON-encoded migrationState
+	// recorded by Migrate, read back by Restore/RestoreWithoutReconcile on
+	// the target management cluster.
+	migrationStateAnnotation = "gcp.openshift.io/migration-state"
+)
+
+// migrationState is the reconstructable state Migrate records onto a
+// Machine's annotations so that a target management cluster can adopt its
+// GCE instance without recreating it.
+type migrationState struct {
+	InstanceSelfLink string `json:"instanceSelfLink"`
+	Zone             string `json:"zone"`
+}
+
+// Migrate detaches machine's GCE instance metadata and IAM bindings from
+// this management cluster and records enough state on the Machine's
+// annotations for a target cluster to adopt the instance via Restore or
+// RestoreWithoutReconcile, without deleting the instance itself. It is the
+// first of the two phases Delete used to perform in one step, split out to
+// support Gardener-style control-plane migration.
+func (a *Actuator) Migrate(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) error {
+	klog.Infof("%s: Migrating machine", machine.Name)
+	scope, err := newMachineScope(machineScopeParams{
+		machineClient: a.machineClient,
+		coreClient:    a.coreClient,
+		machine:       machine,
+	})
+	if err != nil {
+		fmtErr := fmt.Sprintf(scopeFailFmt, machine.Name, err)
+		return a.handleMachineError(machine, apierrors.DeleteMachine(fmtErr), migrateEventAction)
+	}
+
+	reconciler := newReconciler(scope)
+	state, err := reconciler.detach()
+	if err != nil {
+		return a.handleMachineError(machine, apierrors.DeleteMachine(err.Error()), migrateEventAction)
+	}
+
+	if err := recordMigrationState(machine, migrationState{
+		InstanceSelfLink: state.InstanceSelfLink,
+		Zone:             state.Zone,
+	}); err != nil {
+		return a.handleMachineError(machine, apierrors.DeleteMachine(err.Error()), migrateEventAction)
+	}
+
+	if err := a.persistMachine(ctx, machine); err != nil {
+		return a.handleMachineError(machine, apierrors.DeleteMachine(err.Error()), migrateEventAction)
+	}
+
+	a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, migrateEventAction, "Migrated machine %v", machine.Name)
+	return nil
+}
+
+// Restore reconstitutes this Actuator's internal bookkeeping for machine
+// from the migrationState annotation Migrate recorded, then reconciles the
+// Machine so its status converges with the adopted instance. Unlike Create,
+// it never provisions a new instance: reconciler.restore() is expected to
+// find the existing instance at the recorded self link.
+func (a *Actuator) Restore(ctx context.Context, cluster *clusterv1.Cluster, machine *machinev1.Machine) error {
+	klog.Infof("%s: Restoring machine", machine.Name)
+	state, err := readMigrationState(machine)
+	if err != nil {
+		return a.handleMachineError(machine, apierrors.UpdateMachine(err.Error()), restoreEventAction)
+	}
+
+	scope, err := newMachineScope(machineScopeParams{
+		machineClient: a.machineClient,
+		coreClient:    a.coreClient,
+		machine:       machine,
+	})
+	if err != nil {
+		fmtErr := fmt.Sprintf(scopeFailFmt, machine.Name, err)
+		return a.handleMachineError(machine, apierrors.UpdateMachine(fmtErr), restoreEventAction)
+	}
+
+	if err := newReconciler(scope).restore(state.InstanceSelfLink, state.Zone); err != nil {
+		return a.handleMachineError(machine, apierrors.UpdateMachine(err.Error()), restoreEventAction)
+	}
+
+	// Adoption succeeded: clear the migration-state annotation the same way
+	// RestoreWithoutReconcile does, so a later accidental Restore call can't
+	// re-read this now-stale state and restore() against a self link the
+	// instance may no longer be at.
+	delete(machine.Annotations, migrationStateAnnotation)
+
+	a.eventRecorder.Eventf(machine, corev1.EventTypeNormal, restoreEventAction, "Restored machine %v", machine.Name)
+	return a.persistMachine(ctx, machine)
+}
+
+// RestoreWithoutReconcile adopts machine's GCE instance as a bookkeeping step
+// only: it checks that the migrationState annotation Migrate recorded is
+// present and decodes, then clears it and persists the Machine. Unlike
+// Restore it never calls into the reconciler, so the recorded self link and
+// zone are not used and adoption cannot itself trigger the Update this
+// actuator would normally issue in response to a spec/status change. It
+// exists for a target management cluster to take ownership of a live
+// instance ahead of the first real reconcile.
+func (a *Actuator) RestoreWithoutReconcile(ctx context.Context, machine *machinev1.Machine) error {
+	klog.Infof("%s: Restoring machine without reconcile", machine.Name)
+	if _, err := readMigrationState(machine); err != nil {
+		return fmt.Errorf("%s: failed to restore without reconcile: %v", machine.Name, err)
+	}
+
+	delete(machine.Annotations, migrationStateAnnotation)
+	return a.persistMachine(ctx, machine)
+}
+
+func recordMigrationState(machine *machinev1.Machine, state migrationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal migration state for machine %s: %v", machine.Name, err)
+	}
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[migrationStateAnnotation] = string(data)
+	return nil
+}
+
+func readMigrationState(machine *machinev1.Machine) (migrationState, error) {
+	raw, ok := machine.Annotations[migrationStateAnnotation]
+	if !ok {
+		return migrationState{}, fmt.Errorf("machine %s has no %s annotation to restore from", machine.Name, migrationStateAnnotation)
+	}
+	var state migrationState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return migrationState{}, fmt.Errorf("failed to unmarshal migration state for machine %s: %v", machine.Name, err)
+	}
+	return state, nil
+}
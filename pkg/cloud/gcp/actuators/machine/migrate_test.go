@@ -0,0 +1,51 @@
+package machine
+
+import (
+	"testing"
+
+	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMigrationStateRoundTrip(t *testing.T) {
+	machine := &machinev1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+	want := migrationState{
+		InstanceSelfLink: "https://www.googleapis.com/compute/v1/projects/p/zones/us-east1-b/instances/test-machine",
+		Zone:             "us-east1-b",
+	}
+
+	if err := recordMigrationState(machine, want); err != nil {
+		t.Fatalf("recordMigrationState returned error: %v", err)
+	}
+
+	if _, ok := machine.Annotations[migrationStateAnnotation]; !ok {
+		t.Fatalf("expected %s annotation to be set", migrationStateAnnotation)
+	}
+
+	got, err := readMigrationState(machine)
+	if err != nil {
+		t.Fatalf("readMigrationState returned error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round-tripped state = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadMigrationStateMissingAnnotation(t *testing.T) {
+	machine := &machinev1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+
+	if _, err := readMigrationState(machine); err == nil {
+		t.Fatalf("expected an error reading migration state with no annotation set")
+	}
+}
+
+func TestReadMigrationStateInvalidJSON(t *testing.T) {
+	machine := &machinev1.Machine{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test-machine",
+		Annotations: map[string]string{migrationStateAnnotation: "not-json"},
+	}}
+
+	if _, err := readMigrationState(machine); err == nil {
+		t.Fatalf("expected an error reading malformed migration state")
+	}
+}
@@ -0,0 +1,135 @@
+package machine
+
+import (
+	"fmt"
+	"time"
+
+	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// deletionPollInterval is how often a registered deletion watch polls GCP to
+// check whether the underlying instance has gone away. It is intentionally
+// shorter than the machine controller's resync period so that a process
+// restart mid-delete no longer has to wait out the full resync to notice the
+// instance is gone.
+const deletionPollInterval = 15 * time.Second
+
+// deletionPollTimeout bounds how long a deletion watch keeps polling GCP for
+// a single machine. removeDeletionWatch tears a watch down once Delete
+// observes the instance gone, but if the Machine is force-deleted (finalizer
+// stripped) out of band, or the instance never disappears, nothing ever
+// calls removeDeletionWatch; without this bound the goroutine - and the
+// scope/credentials it rebuilds each tick - would poll forever.
+const deletionPollTimeout = 30 * time.Minute
+
+// deletionWatch tracks the goroutine polling GCP on behalf of a single
+// machine whose instance was still present when Delete was last invoked.
+type deletionWatch struct {
+	stop chan struct{}
+}
+
+// SetupDeletionWatch wires a.deletionEvents into ctrl so that generic events
+// sent on that channel enqueue a reconcile for the owning Machine. It must be
+// called once while the controller is being built.
+func (a *Actuator) SetupDeletionWatch(ctrl controller.Controller) error {
+	a.controller = ctrl
+	a.deletionEvents = make(chan event.GenericEvent)
+	return ctrl.Watch(&source.Channel{Source: a.deletionEvents}, &handler.EnqueueRequestForObject{})
+}
+
+// ensureDeletionWatch registers a watch for machine if one is not already
+// running, deduplicated by machine UID. The watch polls GCP until it reports
+// the underlying instance gone, then enqueues a reconcile for the owning
+// Machine and tears itself down. Each poll rebuilds its own scope and
+// reconciler rather than reusing the one Delete constructed, but always from
+// the machine object passed here: edits made to the Machine after the watch
+// was registered, including to its providerSpec, are not seen by the watch.
+func (a *Actuator) ensureDeletionWatch(machine *machinev1.Machine) {
+	if a.controller == nil || a.deletionEvents == nil {
+		return
+	}
+
+	uid := machine.UID
+	a.deletionWatchesMu.Lock()
+	if a.deletionWatches == nil {
+		a.deletionWatches = make(map[types.UID]*deletionWatch)
+	}
+	if _, ok := a.deletionWatches[uid]; ok {
+		a.deletionWatchesMu.Unlock()
+		return
+	}
+	w := &deletionWatch{stop: make(chan struct{})}
+	a.deletionWatches[uid] = w
+	a.deletionWatchesMu.Unlock()
+
+	klog.Infof("%s: instance still present, registering deletion watch", machine.Name)
+	go a.pollUntilDeleted(machine, w)
+}
+
+// instanceExists rebuilds a scope and reconciler for machine and checks
+// whether its underlying GCP instance is still present. It is split out of
+// pollUntilDeleted so every poll tick builds a fresh scope instead of reusing
+// one captured when the watch was registered; machine itself is the object
+// the watch was registered with, not a re-fetched copy.
+func (a *Actuator) instanceExists(machine *machinev1.Machine) (bool, error) {
+	scope, err := newMachineScope(machineScopeParams{
+		machineClient: a.machineClient,
+		coreClient:    a.coreClient,
+		machine:       machine,
+	})
+	if err != nil {
+		return false, fmt.Errorf(scopeFailFmt, machine.Name, err)
+	}
+	return newReconciler(scope).exists()
+}
+
+// removeDeletionWatch tears down the watch registered for uid, if any. It is
+// called once Delete has observed that the instance is gone.
+func (a *Actuator) removeDeletionWatch(uid types.UID) {
+	a.deletionWatchesMu.Lock()
+	w, ok := a.deletionWatches[uid]
+	if ok {
+		delete(a.deletionWatches, uid)
+	}
+	a.deletionWatchesMu.Unlock()
+	if ok {
+		close(w.stop)
+	}
+}
+
+func (a *Actuator) pollUntilDeleted(machine *machinev1.Machine, w *deletionWatch) {
+	ticker := time.NewTicker(deletionPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(deletionPollTimeout)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-deadline:
+			klog.Errorf("%s: deletion watch gave up after %s without confirming instance deletion", machine.Name, deletionPollTimeout)
+			a.removeDeletionWatch(machine.UID)
+			return
+		case <-ticker.C:
+			stillThere, err := a.instanceExists(machine)
+			if err != nil {
+				klog.Errorf("%s: deletion watch failed to check instance status: %v", machine.Name, err)
+				continue
+			}
+			if !stillThere {
+				klog.Infof("%s: instance no longer present, enqueueing reconcile", machine.Name)
+				select {
+				case a.deletionEvents <- event.GenericEvent{Meta: machine.GetObjectMeta(), Object: machine}:
+				case <-w.stop:
+				}
+				a.removeDeletionWatch(machine.UID)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package machineset
+
+import (
+	"container/list"
+	"sync"
+)
+
+// machineTypeCacheSize bounds how many zone/machine-type lookups are kept
+// around at once. GCP projects rarely use more than a handful of distinct
+// machine types across a handful of zones, so this is generous headroom
+// while still protecting against unbounded growth.
+const machineTypeCacheSize = 256
+
+// machineTypeCache is a simple LRU cache of machineTypeInfo keyed by
+// "<zone>/<machineType>", used to avoid re-hitting the GCE machineTypes.get
+// API on every MachineSet reconcile.
+type machineTypeCache struct {
+	mu       sync.Mutex
+	ll       *list.List
+	items    map[string]*list.Element
+	capacity int
+}
+
+type cacheEntry struct {
+	key   string
+	value machineTypeInfo
+}
+
+func newMachineTypeCache() *machineTypeCache {
+	return &machineTypeCache{
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		capacity: machineTypeCacheSize,
+	}
+}
+
+func (c *machineTypeCache) get(key string) (machineTypeInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return machineTypeInfo{}, false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+func (c *machineTypeCache) add(key string, value machineTypeInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*cacheEntry).value = value
+		return
+	}
+	elem := c.ll.PushFront(&cacheEntry{key: key, value: value})
+	c.items[key] = elem
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package machineset implements a scale-from-zero sub-controller for GCP,
+// parallel to the machine actuator in
+// github.com/openshift/cluster-api-provider-gcp/pkg/cloud/gcp/actuators/machine.
+// It reconciles MachineSets rather than Machines: instead of provisioning
+// instances, it resolves each MachineSet's providerSpec.machineType against
+// the GCE machineTypes.get API and stamps the result onto well-known
+// annotations so that cluster-autoscaler can size a MachineSet with zero
+// replicas without ever having to create an instance to find out its shape.
+package machineset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	machinev1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/klog"
+	controllerclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations populated on a MachineSet so cluster-autoscaler can size it
+// without provisioning an instance. These mirror the keys already used by
+// the other in-tree actuators' scale-from-zero support (e.g. vSphere's in
+// machine-api-operator).
+const (
+	cpuKey    = "machine.openshift.io/vCPU"
+	memoryKey = "machine.openshift.io/memoryMb"
+	gpuKey    = "machine.openshift.io/GPU"
+	archKey   = "machine.openshift.io/arch"
+)
+
+// armMachineTypePrefix identifies GCE Tau T2A machine types, the only
+// family currently offered on arm64. Every other machine type is amd64.
+const armMachineTypePrefix = "t2a-"
+
+// fallbackZoneSuffixes is tried, in order, against a MachineSet's region
+// when its providerSpec does not pin a zone, so a machine type lookup can
+// still be resolved instead of leaving the MachineSet unannotated.
+var fallbackZoneSuffixes = []string{"-a", "-b", "-c", "-f"}
+
+// gcpProviderSpec is the subset of GCPMachineProviderSpec this reconciler
+// needs. It is decoded directly from the providerSpec JSON so this package
+// does not have to depend on the machine actuator's internal types.
+type gcpProviderSpec struct {
+	Region            string             `json:"region"`
+	Zone              string             `json:"zone"`
+	MachineType       string             `json:"machineType"`
+	GuestAccelerators []guestAccelerator `json:"guestAccelerators"`
+}
+
+type guestAccelerator struct {
+	AcceleratorType  string `json:"acceleratorType"`
+	AcceleratorCount int32  `json:"acceleratorCount"`
+}
+
+// machineTypeInfo is the resolved shape of a machine type, independent of
+// the compute API's wire format.
+type machineTypeInfo struct {
+	vCPU     int64
+	memoryMb int64
+	arch     string
+}
+
+// MachineTypesService is the subset of the GCE API this reconciler needs,
+// narrowed so tests can provide a fake instead of a real compute.Service.
+type MachineTypesService interface {
+	Get(project, zone, machineType string) (*compute.MachineType, error)
+}
+
+// ReconcilerParams holds parameter information for Reconciler.
+type ReconcilerParams struct {
+	CoreClient   controllerclient.Client
+	MachineTypes MachineTypesService
+	Project      string
+}
+
+// Reconciler reconciles MachineSets to populate the scale-from-zero
+// annotations cluster-autoscaler reads instead of an instance.
+type Reconciler struct {
+	coreClient   controllerclient.Client
+	machineTypes MachineTypesService
+	project      string
+	cache        *machineTypeCache
+}
+
+// NewReconciler returns a Reconciler.
+func NewReconciler(params ReconcilerParams) *Reconciler {
+	return &Reconciler{
+		coreClient:   params.CoreClient,
+		machineTypes: params.MachineTypes,
+		project:      params.Project,
+		cache:        newMachineTypeCache(),
+	}
+}
+
+// Reconcile resolves machineSet's providerSpec.machineType and updates its
+// scale-from-zero annotations accordingly.
+func (r *Reconciler) Reconcile(ctx context.Context, machineSet *machinev1.MachineSet) error {
+	spec, err := decodeProviderSpec(machineSet)
+	if err != nil {
+		return fmt.Errorf("failed to decode providerSpec for MachineSet %s: %v", machineSet.Name, err)
+	}
+	if spec.MachineType == "" {
+		klog.V(4).Infof("%s: providerSpec has no machineType, skipping scale-from-zero annotations", machineSet.Name)
+		return nil
+	}
+
+	info, err := r.resolveMachineType(spec)
+	if err != nil {
+		return fmt.Errorf("failed to resolve machine type %q for MachineSet %s: %v", spec.MachineType, machineSet.Name, err)
+	}
+
+	gpuCount := int32(0)
+	for _, accel := range spec.GuestAccelerators {
+		gpuCount += accel.AcceleratorCount
+	}
+
+	desired := map[string]string{
+		cpuKey:    strconv.FormatInt(info.vCPU, 10),
+		memoryKey: strconv.FormatInt(info.memoryMb, 10),
+		gpuKey:    strconv.FormatInt(int64(gpuCount), 10),
+		archKey:   info.arch,
+	}
+	if annotationsUpToDate(machineSet.Annotations, desired) {
+		return nil
+	}
+
+	if machineSet.Annotations == nil {
+		machineSet.Annotations = map[string]string{}
+	}
+	for k, v := range desired {
+		machineSet.Annotations[k] = v
+	}
+
+	return r.coreClient.Update(ctx, machineSet)
+}
+
+// annotationsUpToDate reports whether existing already has every key/value
+// pair in desired, so Reconcile can skip the Update call when a reconcile
+// recomputes the same scale-from-zero annotations it wrote last time.
+func annotationsUpToDate(existing, desired map[string]string) bool {
+	for k, v := range desired {
+		if existing[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveMachineType looks up spec.MachineType, trying spec.Zone first and
+// falling back to fallbackZoneSuffixes against spec.Region when no zone is
+// pinned, caching each successful lookup per zone/machine-type pair.
+func (r *Reconciler) resolveMachineType(spec *gcpProviderSpec) (machineTypeInfo, error) {
+	zones := []string{spec.Zone}
+	if spec.Zone == "" {
+		for _, suffix := range fallbackZoneSuffixes {
+			zones = append(zones, spec.Region+suffix)
+		}
+	}
+
+	var lastErr error
+	for _, zone := range zones {
+		if zone == "" {
+			continue
+		}
+		cacheKey := zone + "/" + spec.MachineType
+		if info, ok := r.cache.get(cacheKey); ok {
+			return info, nil
+		}
+
+		mt, err := r.machineTypes.Get(r.project, zone, spec.MachineType)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		info := machineTypeInfo{
+			vCPU:     mt.GuestCpus,
+			memoryMb: mt.MemoryMb,
+			arch:     archForMachineType(spec.MachineType),
+		}
+		r.cache.add(cacheKey, info)
+		return info, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no zone available to resolve machine type %q", spec.MachineType)
+	}
+	return machineTypeInfo{}, lastErr
+}
+
+func archForMachineType(machineType string) string {
+	if strings.HasPrefix(machineType, armMachineTypePrefix) {
+		return "arm64"
+	}
+	return "amd64"
+}
+
+func decodeProviderSpec(machineSet *machinev1.MachineSet) (*gcpProviderSpec, error) {
+	raw := machineSet.Spec.Template.Spec.ProviderSpec.Value
+	if raw == nil {
+		return &gcpProviderSpec{}, nil
+	}
+	spec := &gcpProviderSpec{}
+	if err := json.Unmarshal(raw.Raw, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
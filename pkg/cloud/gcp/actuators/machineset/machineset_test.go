@@ -0,0 +1,119 @@
+package machineset
+
+import (
+	"fmt"
+	"testing"
+
+	compute "google.golang.org/api/compute/v1"
+)
+
+// fakeMachineTypes implements MachineTypesService against an in-memory
+// table of "zone/machineType" -> *compute.MachineType, and records every
+// call it receives so tests can assert on fallback order.
+type fakeMachineTypes struct {
+	byZoneAndType map[string]*compute.MachineType
+	calls         []string
+}
+
+func (f *fakeMachineTypes) Get(project, zone, machineType string) (*compute.MachineType, error) {
+	key := zone + "/" + machineType
+	f.calls = append(f.calls, key)
+	mt, ok := f.byZoneAndType[key]
+	if !ok {
+		return nil, fmt.Errorf("machine type %q not found in zone %q", machineType, zone)
+	}
+	return mt, nil
+}
+
+func TestResolveMachineTypeUsesPinnedZone(t *testing.T) {
+	fake := &fakeMachineTypes{byZoneAndType: map[string]*compute.MachineType{
+		"us-east1-b/n1-standard-4": {GuestCpus: 4, MemoryMb: 15360},
+	}}
+	r := &Reconciler{machineTypes: fake, project: "p", cache: newMachineTypeCache()}
+
+	info, err := r.resolveMachineType(&gcpProviderSpec{Zone: "us-east1-b", MachineType: "n1-standard-4"})
+	if err != nil {
+		t.Fatalf("resolveMachineType returned error: %v", err)
+	}
+	if info.vCPU != 4 || info.memoryMb != 15360 {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if info.arch != "amd64" {
+		t.Fatalf("expected amd64 arch, got %q", info.arch)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "us-east1-b/n1-standard-4" {
+		t.Fatalf("expected a single lookup against the pinned zone, got %v", fake.calls)
+	}
+}
+
+func TestResolveMachineTypeFallsBackAcrossZones(t *testing.T) {
+	fake := &fakeMachineTypes{byZoneAndType: map[string]*compute.MachineType{
+		"us-east1-c/n1-standard-4": {GuestCpus: 4, MemoryMb: 15360},
+	}}
+	r := &Reconciler{machineTypes: fake, project: "p", cache: newMachineTypeCache()}
+
+	info, err := r.resolveMachineType(&gcpProviderSpec{Region: "us-east1", MachineType: "n1-standard-4"})
+	if err != nil {
+		t.Fatalf("resolveMachineType returned error: %v", err)
+	}
+	if info.vCPU != 4 {
+		t.Fatalf("unexpected vCPU: %d", info.vCPU)
+	}
+
+	wantCalls := []string{"us-east1-a/n1-standard-4", "us-east1-b/n1-standard-4", "us-east1-c/n1-standard-4"}
+	if len(fake.calls) != len(wantCalls) {
+		t.Fatalf("expected fallback to try zones in order %v, got %v", wantCalls, fake.calls)
+	}
+	for i, want := range wantCalls {
+		if fake.calls[i] != want {
+			t.Fatalf("call %d: expected %q, got %q", i, want, fake.calls[i])
+		}
+	}
+}
+
+func TestResolveMachineTypeCachesSuccessfulLookup(t *testing.T) {
+	fake := &fakeMachineTypes{byZoneAndType: map[string]*compute.MachineType{
+		"us-east1-b/n1-standard-4": {GuestCpus: 4, MemoryMb: 15360},
+	}}
+	r := &Reconciler{machineTypes: fake, project: "p", cache: newMachineTypeCache()}
+
+	if _, err := r.resolveMachineType(&gcpProviderSpec{Zone: "us-east1-b", MachineType: "n1-standard-4"}); err != nil {
+		t.Fatalf("first resolveMachineType returned error: %v", err)
+	}
+	if _, err := r.resolveMachineType(&gcpProviderSpec{Zone: "us-east1-b", MachineType: "n1-standard-4"}); err != nil {
+		t.Fatalf("second resolveMachineType returned error: %v", err)
+	}
+
+	if len(fake.calls) != 1 {
+		t.Fatalf("expected the second resolve to be served from cache, got calls %v", fake.calls)
+	}
+}
+
+func TestResolveMachineTypeArmPrefix(t *testing.T) {
+	fake := &fakeMachineTypes{byZoneAndType: map[string]*compute.MachineType{
+		"us-central1-a/t2a-standard-4": {GuestCpus: 4, MemoryMb: 16384},
+	}}
+	r := &Reconciler{machineTypes: fake, project: "p", cache: newMachineTypeCache()}
+
+	info, err := r.resolveMachineType(&gcpProviderSpec{Zone: "us-central1-a", MachineType: "t2a-standard-4"})
+	if err != nil {
+		t.Fatalf("resolveMachineType returned error: %v", err)
+	}
+	if info.arch != "arm64" {
+		t.Fatalf("expected arm64 arch for a t2a machine type, got %q", info.arch)
+	}
+}
+
+func TestAnnotationsUpToDate(t *testing.T) {
+	desired := map[string]string{"a": "1", "b": "2"}
+
+	if annotationsUpToDate(map[string]string{"a": "1"}, desired) {
+		t.Fatalf("expected missing key to report out of date")
+	}
+	if annotationsUpToDate(map[string]string{"a": "1", "b": "3"}, desired) {
+		t.Fatalf("expected mismatched value to report out of date")
+	}
+	if !annotationsUpToDate(map[string]string{"a": "1", "b": "2", "c": "extra"}, desired) {
+		t.Fatalf("expected extra unrelated keys to be ignored")
+	}
+}
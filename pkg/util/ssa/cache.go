@@ -0,0 +1,80 @@
+// Package ssa provides a small request cache for Server-Side Apply patches,
+// modeled on upstream cluster-api's internal/util/ssa.Cache. Its purpose is
+// to let a controller skip issuing a Patch entirely when it can tell, from a
+// previous call, that the apply configuration it is about to send would not
+// change anything on the server.
+package ssa
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// defaultTTL bounds how long a cache entry is trusted for. It exists so a
+// cache entry can't paper over a change made to the object by something
+// other than this controller (e.g. a user edit) for longer than a few
+// reconciles.
+const defaultTTL = 10 * time.Minute
+
+// Cache remembers which (resourceVersion, applyConfiguration) pairs were
+// already sent to the apiserver, so a reconcile that recomputes the same
+// apply configuration against the same resourceVersion can skip the Patch
+// call entirely.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]time.Time
+}
+
+// NewCache returns a Cache whose entries expire after ttl. A ttl of zero
+// uses defaultTTL.
+func NewCache(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Cache{
+		ttl:     ttl,
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Has reports whether key was added and has not yet expired.
+func (c *Cache) Has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiry, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiry) {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// Add records key as seen, resetting its TTL.
+func (c *Cache) Add(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = time.Now().Add(c.ttl)
+}
+
+// RequestIdentifier computes the cache key for an apply patch: the object's
+// resourceVersion combined with a hash of the apply configuration being
+// sent. Two calls with the same resourceVersion and the same patch content
+// hash to the same key, which is what lets Cache recognize a no-op Patch.
+func RequestIdentifier(resourceVersion string, applyConfiguration interface{}) (string, error) {
+	data, err := json.Marshal(applyConfiguration)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal apply configuration: %v", err)
+	}
+	h := fnv.New64a()
+	if _, err := h.Write(data); err != nil {
+		return "", fmt.Errorf("failed to hash apply configuration: %v", err)
+	}
+	return fmt.Sprintf("%s/%x", resourceVersion, h.Sum64()), nil
+}
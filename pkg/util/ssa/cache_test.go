@@ -0,0 +1,81 @@
+package ssa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheHitSkipsRepeatedKey(t *testing.T) {
+	c := NewCache(time.Minute)
+
+	key, err := RequestIdentifier("1", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+
+	if c.Has(key) {
+		t.Fatalf("expected new cache to not have key before it is added")
+	}
+
+	c.Add(key)
+
+	if !c.Has(key) {
+		t.Fatalf("expected cache to have key after Add")
+	}
+}
+
+func TestCacheMissOnDifferentContent(t *testing.T) {
+	keyA, err := RequestIdentifier("1", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+	keyB, err := RequestIdentifier("1", map[string]string{"a": "c"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+
+	if keyA == keyB {
+		t.Fatalf("expected different content to hash to different keys, got %q for both", keyA)
+	}
+
+	c := NewCache(time.Minute)
+	c.Add(keyA)
+
+	if c.Has(keyB) {
+		t.Fatalf("expected cache miss for a key that was never added")
+	}
+}
+
+func TestCacheMissOnDifferentResourceVersion(t *testing.T) {
+	keyA, err := RequestIdentifier("1", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+	keyB, err := RequestIdentifier("2", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+
+	c := NewCache(time.Minute)
+	c.Add(keyA)
+
+	if c.Has(keyB) {
+		t.Fatalf("expected cache miss after resourceVersion changes, even with identical content")
+	}
+}
+
+func TestCacheEntryExpires(t *testing.T) {
+	c := NewCache(time.Millisecond)
+
+	key, err := RequestIdentifier("1", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("RequestIdentifier returned error: %v", err)
+	}
+	c.Add(key)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c.Has(key) {
+		t.Fatalf("expected cache entry to have expired")
+	}
+}